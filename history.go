@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// runHistoryCommand implements `wisshrd history <list|forget|clear>` over
+// the same StoredData used to drive the fzf pickers.
+func runHistoryCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: wisshrd history <list|forget <value>|clear <bucket>>")
+	}
+
+	data, err := loadStoredData()
+	if err != nil {
+		return fmt.Errorf("could not load history: %w", err)
+	}
+
+	switch args[0] {
+	case "list":
+		printHistory(data)
+		return nil
+
+	case "forget":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: wisshrd history forget <value>")
+		}
+		forgetValue(data, args[1])
+		return saveStoredData(data)
+
+	case "clear":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: wisshrd history clear <keys|accounts|hosts|jumps>")
+		}
+		if err := clearBucket(data, args[1]); err != nil {
+			return err
+		}
+		return saveStoredData(data)
+
+	default:
+		return fmt.Errorf("unknown history subcommand %q", args[0])
+	}
+}
+
+func printHistory(data *StoredData) {
+	buckets := []struct {
+		name    string
+		entries []StoredEntry
+	}{
+		{"keys", data.Keys},
+		{"accounts", data.Accounts},
+		{"hosts", data.Hosts},
+		{"jumps", data.Jumps},
+	}
+
+	now := time.Now()
+	for _, bucket := range buckets {
+		fmt.Printf("%s:\n", bucket.name)
+		for _, entry := range sortByFrecency(bucket.entries) {
+			fmt.Printf("  %-30s uses=%-4d last_used=%s score=%.3f\n",
+				entry.Value, entry.UseCount, entry.LastUsed.Format(time.RFC3339), frecencyScore(entry, now))
+		}
+	}
+}
+
+// forgetValue removes any entry matching value from every bucket.
+func forgetValue(data *StoredData, value string) {
+	data.Keys = removeValue(data.Keys, value)
+	data.Accounts = removeValue(data.Accounts, value)
+	data.Hosts = removeValue(data.Hosts, value)
+	data.Jumps = removeValue(data.Jumps, value)
+}
+
+func removeValue(entries []StoredEntry, value string) []StoredEntry {
+	kept := make([]StoredEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Value != value {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+func clearBucket(data *StoredData, bucket string) error {
+	switch bucket {
+	case "keys":
+		data.Keys = nil
+	case "accounts":
+		data.Accounts = nil
+	case "hosts":
+		data.Hosts = nil
+	case "jumps":
+		data.Jumps = nil
+	default:
+		return fmt.Errorf("unknown bucket %q (expected keys, accounts, hosts, or jumps)", bucket)
+	}
+	return nil
+}
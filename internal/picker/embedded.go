@@ -0,0 +1,154 @@
+package picker
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// EmbeddedPicker is a small in-house fuzzy-filter picker. It needs no
+// external binary, trading fzf's speed and polish for zero dependencies
+// when the caller runs with --native.
+type EmbeddedPicker struct{}
+
+// Pick renders items filtered by a live subsequence match against the
+// typed query, navigable with the arrow keys. Enter selects the
+// highlighted item; if the query doesn't match anything, Enter returns
+// the typed query itself so wildcard host templates can be instantiated.
+func (EmbeddedPicker) Pick(items []string, prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("picker: stdin is not a terminal")
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("could not set terminal to raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	var query string
+	selected := 0
+
+	render := func(matches []string) {
+		fmt.Printf("\r\x1b[K%s (%d/%d) > %s\r\n", prompt, len(matches), len(items), query)
+		for i, m := range matches {
+			if i >= 10 {
+				break
+			}
+			marker := "  "
+			if i == selected {
+				marker = "> "
+			}
+			fmt.Printf("\r\x1b[K%s%s\r\n", marker, m)
+		}
+		lines := len(matches)
+		if lines > 10 {
+			lines = 10
+		}
+		fmt.Printf("\x1b[%dA", lines+1)
+	}
+
+	matches := fuzzyFilter(items, query)
+	render(matches)
+
+	buf := make([]byte, 3)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case n == 1 && (buf[0] == '\r' || buf[0] == '\n'):
+			fmt.Print("\r\n")
+			if len(matches) > 0 && selected < len(matches) {
+				return matches[selected], nil
+			}
+			return query, nil
+		case n == 1 && buf[0] == 3: // Ctrl-C
+			fmt.Print("\r\n")
+			return "", fmt.Errorf("picker: cancelled")
+		case n == 1 && buf[0] == 127: // backspace
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+		case n == 3 && buf[0] == 27 && buf[1] == '[' && buf[2] == 'A': // up
+			if selected > 0 {
+				selected--
+			}
+		case n == 3 && buf[0] == 27 && buf[1] == '[' && buf[2] == 'B': // down
+			if selected < len(matches)-1 {
+				selected++
+			}
+		case n == 1 && buf[0] >= 32 && buf[0] < 127:
+			query += string(buf[0])
+		default:
+			continue
+		}
+
+		matches = fuzzyFilter(items, query)
+		if selected >= len(matches) {
+			selected = 0
+		}
+		render(matches)
+	}
+}
+
+// fuzzyFilter returns items containing query's characters as a
+// subsequence (case-insensitive), ordered by how tightly they match.
+func fuzzyFilter(items []string, query string) []string {
+	if query == "" {
+		return items
+	}
+
+	type scored struct {
+		value string
+		span  int
+	}
+	var results []scored
+
+	q := strings.ToLower(query)
+	for _, item := range items {
+		span, ok := subsequenceSpan(strings.ToLower(item), q)
+		if ok {
+			results = append(results, scored{value: item, span: span})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].span < results[j].span })
+
+	matches := make([]string, len(results))
+	for i, r := range results {
+		matches[i] = r.value
+	}
+	return matches
+}
+
+// subsequenceSpan reports whether query appears in s as a subsequence,
+// and the distance between the first and last matched characters of the
+// leftmost greedy match, used to rank tighter matches first. That greedy
+// match is not necessarily the shortest one in s.
+func subsequenceSpan(s, query string) (int, bool) {
+	start := -1
+	end := -1
+	qi := 0
+	for i := 0; i < len(s) && qi < len(query); i++ {
+		if s[i] == query[qi] {
+			if start == -1 {
+				start = i
+			}
+			end = i
+			qi++
+		}
+	}
+	if qi < len(query) {
+		return 0, false
+	}
+	return end - start, true
+}
+
+var _ Picker = EmbeddedPicker{}
@@ -0,0 +1,67 @@
+package picker
+
+import "testing"
+
+func TestSubsequenceSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		s, query  string
+		wantSpan  int
+		wantMatch bool
+	}{
+		{"exact match", "web-1", "web-1", 4, true},
+		{"subsequence", "web-1.example.com", "w1e", 6, true},
+		{"empty query matches at zero width", "web-1", "", 0, true},
+		{"no match", "web-1", "xyz", 0, false},
+		{"query longer than s", "ab", "abc", 0, false},
+		{"tighter span for consecutive chars", "web", "eb", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span, ok := subsequenceSpan(tt.s, tt.query)
+			if ok != tt.wantMatch {
+				t.Fatalf("subsequenceSpan(%q, %q) match = %v, want %v", tt.s, tt.query, ok, tt.wantMatch)
+			}
+			if ok && span != tt.wantSpan {
+				t.Errorf("subsequenceSpan(%q, %q) span = %d, want %d", tt.s, tt.query, span, tt.wantSpan)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilter_EmptyQueryReturnsAllItemsUnordered(t *testing.T) {
+	items := []string{"web-1", "web-2", "db-1"}
+	got := fuzzyFilter(items, "")
+
+	if len(got) != len(items) {
+		t.Fatalf("fuzzyFilter with empty query returned %d items, want %d", len(got), len(items))
+	}
+}
+
+func TestFuzzyFilter_FiltersAndRanksByTightestSpan(t *testing.T) {
+	items := []string{"database-1", "db-1", "web-2"}
+
+	got := fuzzyFilter(items, "db1")
+
+	if len(got) != 2 {
+		t.Fatalf("fuzzyFilter(%v, \"db1\") = %v, want 2 matches", items, got)
+	}
+	if got[0] != "db-1" {
+		t.Errorf("fuzzyFilter ranked %q first, want the tighter match %q", got[0], "db-1")
+	}
+}
+
+func TestFuzzyFilter_CaseInsensitive(t *testing.T) {
+	got := fuzzyFilter([]string{"Web-1"}, "WEB")
+	if len(got) != 1 {
+		t.Errorf("fuzzyFilter should match case-insensitively, got %v", got)
+	}
+}
+
+func TestFuzzyFilter_NoMatchesReturnsEmpty(t *testing.T) {
+	got := fuzzyFilter([]string{"web-1", "web-2"}, "zzz")
+	if len(got) != 0 {
+		t.Errorf("fuzzyFilter(%v, \"zzz\") = %v, want no matches", []string{"web-1", "web-2"}, got)
+	}
+}
@@ -0,0 +1,64 @@
+package picker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FzfPicker shells out to the fzf(1) binary. It is the default Picker,
+// kept so wisshrd's look and feel doesn't change for anyone who already
+// has fzf installed.
+type FzfPicker struct{}
+
+// Pick runs fzf over items, returning the selected item or, if the query
+// doesn't match anything, the typed query itself (via fzf's
+// --print-query).
+func (FzfPicker) Pick(items []string, prompt string) (string, error) {
+	args := []string{
+		"--height", "20%",
+		"--min-height", "1",
+		"--print-query",
+		"--no-margin",
+		"--no-padding",
+		"--prompt", fmt.Sprintf("%s (%d options) > ", prompt, len(items)),
+	}
+	cmd := exec.Command("fzf", args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return "", err
+	}
+
+	go func() {
+		defer stdin.Close()
+		for _, item := range items {
+			fmt.Fprintln(stdin, item)
+		}
+	}()
+
+	output, err := cmd.Output()
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if ok && exitErr.ExitCode() == 1 {
+			lines := strings.Split(string(output), "\n")
+			if len(lines) > 0 && lines[0] != "" {
+				return lines[0], nil
+			}
+		}
+		return "", err
+	}
+
+	lines := strings.Split(string(output), "\n")
+	if len(lines) >= 2 {
+		selection := strings.TrimSpace(lines[1])
+		if selection != "" {
+			return selection, nil
+		}
+		return strings.TrimSpace(lines[0]), nil
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+var _ Picker = FzfPicker{}
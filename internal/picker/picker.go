@@ -0,0 +1,12 @@
+// Package picker provides interactive, fuzzy-filtered item selection,
+// either by shelling out to fzf(1) or via a small embedded matcher that
+// needs no external binary.
+package picker
+
+// Picker selects one item out of items, showing prompt as the picker's
+// label. If the user types a query that doesn't match any item, Picker
+// returns that typed query verbatim so callers can treat it as a
+// freeform value (e.g. instantiating a wildcard host template).
+type Picker interface {
+	Pick(items []string, prompt string) (string, error)
+}
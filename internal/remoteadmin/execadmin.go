@@ -0,0 +1,200 @@
+package remoteadmin
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ExecAdmin implements ISSHAdmin by shelling out to the `ssh` binary
+// against target (a host alias or user@host string), the same way the
+// rest of wisshrd talks to remote hosts today.
+type ExecAdmin struct {
+	Target string
+}
+
+// NewExecAdmin returns an ISSHAdmin that manages target over an exec'd
+// ssh binary.
+func NewExecAdmin(target string) *ExecAdmin {
+	return &ExecAdmin{Target: target}
+}
+
+const sshdConfigPath = "/etc/ssh/sshd_config"
+
+func (a *ExecAdmin) run(remoteCmd string) (string, error) {
+	return a.runWithStdin(remoteCmd, "")
+}
+
+// runWithStdin runs remoteCmd on the target with stdin piped to it over
+// the ssh connection. Used instead of interpolating arbitrary content
+// into the command string, so remote content can never be confused with
+// shell syntax.
+func (a *ExecAdmin) runWithStdin(remoteCmd, stdin string) (string, error) {
+	cmd := exec.Command("ssh", a.Target, remoteCmd)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("remote command %q failed: %w: %s", remoteCmd, err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// GetInfo reads and parses the remote sshd_config.
+func (a *ExecAdmin) GetInfo() (Info, error) {
+	content, err := a.run(fmt.Sprintf("cat %s", sshdConfigPath))
+	if err != nil {
+		return Info{}, err
+	}
+	return parseSSHDConfig(content), nil
+}
+
+func parseSSHDConfig(content string) Info {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		key := fields[0]
+		if _, exists := values[key]; exists {
+			continue // first occurrence wins, matching sshd(8) semantics
+		}
+		values[key] = strings.Join(fields[1:], " ")
+	}
+
+	return Info{
+		Port:                   values["Port"],
+		ListenAddress:          values["ListenAddress"],
+		PasswordAuthentication: values["PasswordAuthentication"],
+		PubkeyAuthentication:   values["PubkeyAuthentication"],
+		PermitRootLogin:        values["PermitRootLogin"],
+		UseDNS:                 values["UseDNS"],
+	}
+}
+
+// Update sets key to value in the remote sshd_config, validating the
+// result with `sshd -t` before making it durable.
+func (a *ExecAdmin) Update(key, value string) error {
+	content, err := a.run(fmt.Sprintf("cat %s", sshdConfigPath))
+	if err != nil {
+		return err
+	}
+
+	updated := setSSHDConfigValue(content, key, value)
+	return a.UpdateByFile(updated)
+}
+
+func setSSHDConfigValue(content, key, value string) string {
+	lines := strings.Split(content, "\n")
+	replaced := false
+	for i, line := range lines {
+		fields := strings.Fields(strings.TrimSpace(line))
+		if len(fields) >= 1 && strings.EqualFold(fields[0], key) {
+			lines[i] = fmt.Sprintf("%s %s", key, value)
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		lines = append(lines, fmt.Sprintf("%s %s", key, value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// UpdateByFile atomically replaces the remote sshd_config with content:
+// it writes to a temp file, validates it with `sshd -t`, then moves it
+// into place only if validation succeeds.
+func (a *ExecAdmin) UpdateByFile(content string) error {
+	tmpPath := fmt.Sprintf("%s.wisshrd-tmp", sshdConfigPath)
+
+	if _, err := a.runWithStdin(fmt.Sprintf("cat > %s", tmpPath), content); err != nil {
+		return fmt.Errorf("could not stage sshd_config: %w", err)
+	}
+
+	if _, err := a.run(fmt.Sprintf("sudo sshd -t -f %s", tmpPath)); err != nil {
+		a.run(fmt.Sprintf("rm -f %s", tmpPath))
+		return fmt.Errorf("sshd_config failed validation, not applied: %w", err)
+	}
+
+	if _, err := a.run(fmt.Sprintf("sudo mv %s %s", tmpPath, sshdConfigPath)); err != nil {
+		return fmt.Errorf("could not install validated sshd_config: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateKey creates a new ed25519 keypair on the remote host and
+// installs the public half into ~/.ssh/authorized_keys.
+func (a *ExecAdmin) GenerateKey(comment string) (string, error) {
+	keyPath := fmt.Sprintf("~/.ssh/wisshrd-%d", time.Now().UnixNano())
+	genCmd := fmt.Sprintf("ssh-keygen -t ed25519 -N '' -C %q -f %s", comment, keyPath)
+	if _, err := a.run(genCmd); err != nil {
+		return "", fmt.Errorf("could not generate remote keypair: %w", err)
+	}
+
+	pubKey, err := a.run(fmt.Sprintf("cat %s.pub", keyPath))
+	if err != nil {
+		return "", fmt.Errorf("could not read generated public key: %w", err)
+	}
+	pubKey = strings.TrimSpace(pubKey)
+
+	installCmd := fmt.Sprintf("mkdir -p ~/.ssh && echo %q >> ~/.ssh/authorized_keys", pubKey)
+	if _, err := a.run(installCmd); err != nil {
+		return "", fmt.Errorf("could not install public key: %w", err)
+	}
+
+	return pubKey, nil
+}
+
+var authLogLine = regexp.MustCompile(`^(\w{3}\s+\d+\s+[\d:]+)\s+\S+\s+sshd.*?(?:from|rhost=)\s*([0-9a-fA-F:.]+)?`)
+
+// LoadLog tails /var/log/auth.log for sshd events matching filter and
+// resolves each entry's source IP via geo.
+func (a *ExecAdmin) LoadLog(filter string, geo GeoLocator) ([]LogEntry, error) {
+	grepCmd := "grep sshd /var/log/auth.log"
+	if filter != "" {
+		grepCmd = fmt.Sprintf("%s | grep %q", grepCmd, filter)
+	}
+
+	output, err := a.run(grepCmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if geo == nil {
+		geo = NoopGeoLocator{}
+	}
+
+	var entries []LogEntry
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := LogEntry{Event: line, Raw: line}
+		if m := authLogLine.FindStringSubmatch(line); m != nil {
+			if ts, err := time.Parse("Jan 2 15:04:05", m[1]); err == nil {
+				entry.Timestamp = ts
+			}
+			entry.IP = m[2]
+		}
+		if entry.IP != "" {
+			if loc, err := geo.Lookup(entry.IP); err == nil {
+				entry.Location = loc
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+var _ ISSHAdmin = (*ExecAdmin)(nil)
@@ -0,0 +1,77 @@
+package remoteadmin
+
+import "testing"
+
+func TestParseSSHDConfig(t *testing.T) {
+	content := "# comment\n" +
+		"Port 2222\n" +
+		"ListenAddress 0.0.0.0\n" +
+		"PasswordAuthentication no\n" +
+		"PubkeyAuthentication yes\n" +
+		"PermitRootLogin prohibit-password\n" +
+		"UseDNS no\n"
+
+	got := parseSSHDConfig(content)
+	want := Info{
+		Port:                   "2222",
+		ListenAddress:          "0.0.0.0",
+		PasswordAuthentication: "no",
+		PubkeyAuthentication:   "yes",
+		PermitRootLogin:        "prohibit-password",
+		UseDNS:                 "no",
+	}
+	if got != want {
+		t.Errorf("parseSSHDConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSSHDConfig_FirstOccurrenceWins(t *testing.T) {
+	content := "Port 22\nPort 2222\n"
+
+	got := parseSSHDConfig(content)
+	if got.Port != "22" {
+		t.Errorf("Port = %q, want %q (first occurrence should win, matching sshd(8))", got.Port, "22")
+	}
+}
+
+func TestParseSSHDConfig_IgnoresCommentsAndBlankLines(t *testing.T) {
+	content := "\n# Port 9999\n  \nPort 22\n"
+
+	got := parseSSHDConfig(content)
+	if got.Port != "22" {
+		t.Errorf("Port = %q, want %q", got.Port, "22")
+	}
+}
+
+func TestSetSSHDConfigValue_ReplacesExistingKey(t *testing.T) {
+	content := "Port 22\nPasswordAuthentication yes\n"
+
+	got := setSSHDConfigValue(content, "Port", "2222")
+
+	want := "Port 2222\nPasswordAuthentication yes\n"
+	if got != want {
+		t.Errorf("setSSHDConfigValue() = %q, want %q", got, want)
+	}
+}
+
+func TestSetSSHDConfigValue_AppendsMissingKey(t *testing.T) {
+	content := "Port 22\n"
+
+	got := setSSHDConfigValue(content, "UseDNS", "no")
+
+	want := "Port 22\n\nUseDNS no"
+	if got != want {
+		t.Errorf("setSSHDConfigValue() = %q, want %q", got, want)
+	}
+}
+
+func TestSetSSHDConfigValue_IsCaseInsensitiveOnKey(t *testing.T) {
+	content := "port 22\n"
+
+	got := setSSHDConfigValue(content, "Port", "2222")
+
+	want := "Port 2222\n"
+	if got != want {
+		t.Errorf("setSSHDConfigValue() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package remoteadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPGeoLocator resolves IPs via the ip-api.com JSON endpoint. It is the
+// GeoLocator wisshrd's server mode passes to LoadLog; LoadLog itself
+// falls back to NoopGeoLocator when given a nil GeoLocator.
+type HTTPGeoLocator struct {
+	Client *http.Client
+}
+
+// NewHTTPGeoLocator returns a GeoLocator with a conservative timeout, so a
+// slow lookup can't stall an auth-log tail.
+func NewHTTPGeoLocator() *HTTPGeoLocator {
+	return &HTTPGeoLocator{Client: &http.Client{Timeout: 3 * time.Second}}
+}
+
+// Lookup returns a "City, Country" string for ip, or an error if the
+// lookup fails or the IP can't be resolved.
+func (g *HTTPGeoLocator) Lookup(ip string) (string, error) {
+	resp, err := g.Client.Get(fmt.Sprintf("http://ip-api.com/json/%s?fields=status,city,country", ip))
+	if err != nil {
+		return "", fmt.Errorf("geolocation lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Status  string `json:"status"`
+		City    string `json:"city"`
+		Country string `json:"country"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("could not decode geolocation response: %w", err)
+	}
+	if result.Status != "success" {
+		return "", fmt.Errorf("geolocation lookup failed for %s", ip)
+	}
+
+	return fmt.Sprintf("%s, %s", result.City, result.Country), nil
+}
+
+var _ GeoLocator = (*HTTPGeoLocator)(nil)
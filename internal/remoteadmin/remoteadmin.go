@@ -0,0 +1,61 @@
+// Package remoteadmin manages a remote host's sshd_config and related
+// server-side SSH state (keys, auth log) over a transport-agnostic
+// ISSHAdmin interface.
+package remoteadmin
+
+import "time"
+
+// Info is the subset of sshd_config settings the server mode cares about.
+type Info struct {
+	Port                   string
+	ListenAddress          string
+	PasswordAuthentication string
+	PubkeyAuthentication   string
+	PermitRootLogin        string
+	UseDNS                 string
+}
+
+// LogEntry is a single sshd auth-log line, optionally enriched with the
+// geolocation of the remote IP.
+type LogEntry struct {
+	Timestamp time.Time
+	Event     string
+	IP        string
+	Location  string
+	Raw       string
+}
+
+// ISSHAdmin manages a remote host's sshd configuration and key material.
+// Implementations may talk to the host over an exec'd ssh binary, a native
+// golang.org/x/crypto/ssh client, mosh, or an ssh-agent forwarder; callers
+// should depend only on this interface.
+type ISSHAdmin interface {
+	// GetInfo reads and parses the remote /etc/ssh/sshd_config.
+	GetInfo() (Info, error)
+	// Update sets a single sshd_config key to value, validating with
+	// `sshd -t` before the change is made durable.
+	Update(key, value string) error
+	// UpdateByFile atomically replaces /etc/ssh/sshd_config with content,
+	// validating with `sshd -t` before moving it into place.
+	UpdateByFile(content string) error
+	// GenerateKey creates a new keypair on the remote host and installs
+	// the public half into ~/.ssh/authorized_keys, returning it.
+	GenerateKey(comment string) (publicKey string, err error)
+	// LoadLog tails /var/log/auth.log for sshd events matching filter
+	// (an empty filter matches everything) and resolves each entry's
+	// source IP to a location via geo.
+	LoadLog(filter string, geo GeoLocator) ([]LogEntry, error)
+}
+
+// GeoLocator resolves an IP address to a human-readable location. Callers
+// that don't need geolocation (or are offline) can pass NoopGeoLocator{}.
+type GeoLocator interface {
+	Lookup(ip string) (string, error)
+}
+
+// NoopGeoLocator is a GeoLocator that never resolves anything, for callers
+// that want LoadLog without a network round-trip per entry.
+type NoopGeoLocator struct{}
+
+// Lookup always returns an empty location.
+func (NoopGeoLocator) Lookup(ip string) (string, error) { return "", nil }
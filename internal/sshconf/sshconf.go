@@ -0,0 +1,302 @@
+// Package sshconf parses OpenSSH client configuration files (~/.ssh/config)
+// into Host/Match blocks and resolves the effective settings for a given
+// alias, following Include directives the way ssh(1) does.
+package sshconf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HostBlock is a single `Host` or `Match` section of an ssh_config file,
+// in the order it was encountered.
+type HostBlock struct {
+	// Patterns holds the space-separated patterns following "Host" (e.g.
+	// "web-*" or "*"). Empty for Match blocks.
+	Patterns []string
+	// Match holds the raw condition text following "Match", if this block
+	// is a Match block rather than a Host block.
+	Match string
+	// Options holds the directives declared inside the block, keyed by
+	// their lower-cased name. Values preserve declaration order for
+	// directives that may repeat (e.g. IdentityFile).
+	Options map[string][]string
+}
+
+// IsWildcard reports whether the block's patterns contain glob characters,
+// meaning it describes a template rather than a concrete host.
+func (b HostBlock) IsWildcard() bool {
+	for _, p := range b.Patterns {
+		if strings.ContainsAny(p, "*?") {
+			return true
+		}
+	}
+	return false
+}
+
+func (b HostBlock) matches(alias string) bool {
+	for _, p := range b.Patterns {
+		negate := strings.HasPrefix(p, "!")
+		pat := strings.TrimPrefix(p, "!")
+		ok, _ := filepath.Match(pat, alias)
+		if ok {
+			if negate {
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveConfig is the resolved, per-host view of an alias after merging
+// every matching Host block in declaration order.
+type EffectiveConfig struct {
+	Alias          string
+	HostName       string
+	User           string
+	Port           string
+	IdentityFile   string
+	ProxyJump      string
+	LocalForward   string
+	RemoteForward  string
+	DynamicForward string
+}
+
+// Parse reads a single ssh_config stream and returns its Host/Match blocks.
+// It does not follow Include directives, since a reader has no notion of a
+// base directory to resolve relative globs against; use ParseFile for that.
+func Parse(r io.Reader) ([]HostBlock, error) {
+	var blocks []HostBlock
+	// Options declared before the first Host/Match line apply globally; we
+	// model that as a block matching everything.
+	current := HostBlock{Patterns: []string{"*"}, Options: map[string][]string{}}
+	blocks = append(blocks, current)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := splitDirective(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(key) {
+		case "host":
+			blocks = append(blocks, HostBlock{
+				Patterns: strings.Fields(value),
+				Options:  map[string][]string{},
+			})
+			continue
+		case "match":
+			blocks = append(blocks, HostBlock{
+				Match:   value,
+				Options: map[string][]string{},
+			})
+			continue
+		}
+
+		last := &blocks[len(blocks)-1]
+		last.Options[strings.ToLower(key)] = append(last.Options[strings.ToLower(key)], value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read ssh config: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// Config is a parsed ssh_config file, ready to resolve aliases against.
+type Config struct {
+	Blocks []HostBlock
+}
+
+// LoadFile parses the ssh_config file at path, expanding any Include
+// directives it contains relative to baseDir (normally ~/.ssh).
+func LoadFile(path, baseDir string) (*Config, error) {
+	blocks, err := ParseFile(path, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Blocks: blocks}, nil
+}
+
+// ParseFile reads the ssh_config file at path and expands any Include
+// directives it contains, relative to baseDir (normally ~/.ssh). Include
+// globs are resolved and spliced in at the point the Include line
+// occurs, so a Host block declared before an Include still takes
+// precedence over a same-named block inside the included file, matching
+// ssh(1)'s first-obtained-value-wins semantics.
+func ParseFile(path, baseDir string) ([]HostBlock, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open ssh config %q: %w", path, err)
+	}
+	defer file.Close()
+
+	var blocks []HostBlock
+	current := HostBlock{Patterns: []string{"*"}, Options: map[string][]string{}}
+	blocks = append(blocks, current)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := splitDirective(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(key) {
+		case "host":
+			blocks = append(blocks, HostBlock{
+				Patterns: strings.Fields(value),
+				Options:  map[string][]string{},
+			})
+			continue
+		case "match":
+			blocks = append(blocks, HostBlock{
+				Match:   value,
+				Options: map[string][]string{},
+			})
+			continue
+		case "include":
+			for _, pattern := range strings.Fields(value) {
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(baseDir, pattern)
+				}
+				matches, err := filepath.Glob(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid Include pattern %q: %w", pattern, err)
+				}
+				for _, included := range matches {
+					includedBlocks, err := ParseFile(included, baseDir)
+					if err != nil {
+						return nil, err
+					}
+					blocks = append(blocks, includedBlocks...)
+				}
+			}
+			continue
+		}
+
+		last := &blocks[len(blocks)-1]
+		last.Options[strings.ToLower(key)] = append(last.Options[strings.ToLower(key)], value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read ssh config: %w", err)
+	}
+
+	return blocks, nil
+}
+
+func splitDirective(line string) (key, value string, err error) {
+	line = strings.TrimSpace(strings.SplitN(line, "#", 2)[0])
+	if line == "" {
+		return "", "", nil
+	}
+
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) == 1 {
+		fields = strings.SplitN(line, "=", 2)
+	}
+	if len(fields) != 2 {
+		return "", "", fmt.Errorf("malformed ssh config line: %q", line)
+	}
+
+	key = strings.TrimSpace(fields[0])
+	value = strings.TrimSpace(strings.Trim(fields[1], "\""))
+	return key, value, nil
+}
+
+// Resolve merges every Host block whose pattern matches alias, in
+// declaration order, following OpenSSH's first-obtained-value-wins rule.
+// Match blocks are ignored, since evaluating their conditions requires
+// runtime context this package does not have. An error is returned only
+// if alias is empty, since any non-empty alias resolves to at least its
+// own name: HostName falls back to alias when no matching block sets it.
+func (c *Config) Resolve(alias string) (EffectiveConfig, error) {
+	if alias == "" {
+		return EffectiveConfig{}, fmt.Errorf("sshconf: alias must not be empty")
+	}
+
+	cfg := EffectiveConfig{Alias: alias}
+
+	for _, block := range c.Blocks {
+		if block.Match != "" || len(block.Patterns) == 0 {
+			continue
+		}
+		if !block.matches(alias) {
+			continue
+		}
+
+		setOnce(&cfg.HostName, block.Options["hostname"])
+		setOnce(&cfg.User, block.Options["user"])
+		setOnce(&cfg.Port, block.Options["port"])
+		setOnce(&cfg.IdentityFile, block.Options["identityfile"])
+		setOnce(&cfg.ProxyJump, block.Options["proxyjump"])
+		setOnce(&cfg.LocalForward, block.Options["localforward"])
+		setOnce(&cfg.RemoteForward, block.Options["remoteforward"])
+		setOnce(&cfg.DynamicForward, block.Options["dynamicforward"])
+	}
+
+	if cfg.HostName == "" {
+		cfg.HostName = alias
+	}
+
+	return cfg, nil
+}
+
+// Aliases returns every concrete (non-wildcard) host alias declared across
+// the config's blocks, and the wildcard blocks kept as instantiable
+// templates, in declaration order. A block counts as a template (once,
+// regardless of how many wildcard patterns it declares) if any of its
+// patterns use glob syntax, matching how ssh(1) treats the whole block as
+// a loosely-matching rule rather than a single concrete alias. Blocks
+// whose only pattern is "*" (including the implicit global block) are
+// returned as neither.
+func (c *Config) Aliases() (concrete []string, templates []HostBlock) {
+	for _, block := range c.Blocks {
+		if block.Match != "" {
+			continue
+		}
+		if block.IsWildcard() {
+			if len(block.Patterns) != 1 || block.Patterns[0] != "*" {
+				templates = append(templates, block)
+			}
+			continue
+		}
+		concrete = append(concrete, block.Patterns...)
+	}
+	return concrete, templates
+}
+
+func setOnce(dst *string, values []string) {
+	if *dst != "" || len(values) == 0 {
+		return
+	}
+	*dst = values[0]
+}
+
+// ParsePort is a small convenience for callers formatting "user@host:port".
+func ParsePort(cfg EffectiveConfig) string {
+	if cfg.Port == "" {
+		return ""
+	}
+	if _, err := strconv.Atoi(cfg.Port); err != nil {
+		return ""
+	}
+	return cfg.Port
+}
@@ -0,0 +1,209 @@
+package sshconf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig_Resolve_FirstMatchWins(t *testing.T) {
+	cfg := &Config{Blocks: []HostBlock{
+		{Patterns: []string{"*"}, Options: map[string][]string{}},
+		{Patterns: []string{"web-*"}, Options: map[string][]string{
+			"hostname": {"10.0.0.1"},
+			"user":     {"deploy"},
+		}},
+		{Patterns: []string{"web-1"}, Options: map[string][]string{
+			"hostname": {"10.0.0.2"},
+			"port":     {"2222"},
+		}},
+	}}
+
+	got, err := cfg.Resolve("web-1")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	// The wildcard block is declared (and thus matched) before the
+	// concrete block, so its HostName/User should win even though the
+	// concrete block also sets HostName.
+	if got.HostName != "10.0.0.1" {
+		t.Errorf("HostName = %q, want %q (first-obtained-value-wins)", got.HostName, "10.0.0.1")
+	}
+	if got.User != "deploy" {
+		t.Errorf("User = %q, want %q", got.User, "deploy")
+	}
+	if got.Port != "2222" {
+		t.Errorf("Port = %q, want %q", got.Port, "2222")
+	}
+}
+
+func TestConfig_Resolve_EmptyAlias(t *testing.T) {
+	cfg := &Config{}
+	if _, err := cfg.Resolve(""); err == nil {
+		t.Error("Resolve(\"\") should return an error")
+	}
+}
+
+func TestConfig_Resolve_DefaultsHostNameToAlias(t *testing.T) {
+	cfg := &Config{Blocks: []HostBlock{
+		{Patterns: []string{"*"}, Options: map[string][]string{}},
+	}}
+
+	got, err := cfg.Resolve("plain-host")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if got.HostName != "plain-host" {
+		t.Errorf("HostName = %q, want alias %q when no HostName is set", got.HostName, "plain-host")
+	}
+}
+
+func TestHostBlock_IsWildcard(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     bool
+	}{
+		{"concrete", []string{"web-1"}, false},
+		{"multiple concrete", []string{"web-1", "web-2"}, false},
+		{"star", []string{"web-*"}, true},
+		{"question mark", []string{"web-?"}, true},
+		{"bare star", []string{"*"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := HostBlock{Patterns: tt.patterns}
+			if got := b.IsWildcard(); got != tt.want {
+				t.Errorf("IsWildcard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Aliases(t *testing.T) {
+	cfg := &Config{Blocks: []HostBlock{
+		{Patterns: []string{"*"}},
+		{Patterns: []string{"web-1", "web-2"}},
+		{Patterns: []string{"web-*"}},
+		{Match: "host web-3"},
+	}}
+
+	concrete, templates := cfg.Aliases()
+
+	if want := []string{"web-1", "web-2"}; !equalStrings(concrete, want) {
+		t.Errorf("concrete = %v, want %v", concrete, want)
+	}
+	if len(templates) != 1 || templates[0].Patterns[0] != "web-*" {
+		t.Errorf("templates = %v, want a single web-* block", templates)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestParseFile_IncludePrecedence verifies that a Host block declared
+// before an Include line in the same file takes precedence over a
+// same-named Host block inside the included file, matching ssh(1)'s
+// first-obtained-value-wins semantics.
+func TestParseFile_IncludePrecedence(t *testing.T) {
+	dir := t.TempDir()
+
+	included := "Host web-1\n  HostName 10.0.0.9\n  User fromincluded\n"
+	if err := os.WriteFile(filepath.Join(dir, "extra.conf"), []byte(included), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	main := strings.Join([]string{
+		"Host web-1",
+		"  HostName 10.0.0.1",
+		"Include extra.conf",
+	}, "\n") + "\n"
+	mainPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(mainPath, []byte(main), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(mainPath, dir)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	got, err := cfg.Resolve("web-1")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	if got.HostName != "10.0.0.1" {
+		t.Errorf("HostName = %q, want %q (declared block before Include should win)", got.HostName, "10.0.0.1")
+	}
+	// User is only set by the included block, so it should still be
+	// picked up even though its HostName lost out.
+	if got.User != "fromincluded" {
+		t.Errorf("User = %q, want %q", got.User, "fromincluded")
+	}
+}
+
+// TestParseFile_OwnBlockAfterIncludeLoses verifies the converse: when the
+// Include line comes before a file's own Host block, the included
+// block's values win since it was obtained first.
+func TestParseFile_OwnBlockAfterIncludeLoses(t *testing.T) {
+	dir := t.TempDir()
+
+	included := "Host web-1\n  HostName 10.0.0.9\n"
+	if err := os.WriteFile(filepath.Join(dir, "extra.conf"), []byte(included), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	main := strings.Join([]string{
+		"Include extra.conf",
+		"Host web-1",
+		"  HostName 10.0.0.1",
+	}, "\n") + "\n"
+	mainPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(mainPath, []byte(main), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(mainPath, dir)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	got, err := cfg.Resolve("web-1")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	if got.HostName != "10.0.0.9" {
+		t.Errorf("HostName = %q, want %q (Include declared before own block should win)", got.HostName, "10.0.0.9")
+	}
+}
+
+func TestParse_GlobalOptionsApplyToAllHosts(t *testing.T) {
+	r := strings.NewReader("User globaluser\nHost web-1\n  HostName 10.0.0.1\n")
+	blocks, err := Parse(r)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	cfg := &Config{Blocks: blocks}
+	got, err := cfg.Resolve("web-1")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if got.User != "globaluser" {
+		t.Errorf("User = %q, want %q", got.User, "globaluser")
+	}
+}
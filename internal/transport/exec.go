@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"wisshrd/internal/sshconf"
+)
+
+// ExecConnector shells out to the system ssh(1) binary. It is the default
+// Connector, kept so wisshrd keeps working anywhere ssh(1) is installed
+// and configured the way the user expects (agent, known_hosts, config
+// quirks all handled by the real client).
+type ExecConnector struct{}
+
+// Connect runs `ssh <target>` with the current process's stdio attached.
+func (ExecConnector) Connect(target sshconf.EffectiveConfig) error {
+	args := []string{BuildDestination(target)}
+	if target.Port != "" {
+		args = append(args, "-p", target.Port)
+	}
+	if target.IdentityFile != "" {
+		args = append(args, "-i", target.IdentityFile)
+	}
+	if target.ProxyJump != "" {
+		args = append(args, "-J", target.ProxyJump)
+	}
+	if target.LocalForward != "" {
+		args = append(args, "-L", target.LocalForward)
+	}
+	if target.RemoteForward != "" {
+		args = append(args, "-R", target.RemoteForward)
+	}
+	if target.DynamicForward != "" {
+		args = append(args, "-D", target.DynamicForward)
+	}
+
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ssh exited with an error: %w", err)
+	}
+	return nil
+}
+
+// BuildDestination renders target as the "user@host" destination string
+// ssh(1) expects.
+func BuildDestination(target sshconf.EffectiveConfig) string {
+	if target.User == "" {
+		return target.HostName
+	}
+	return fmt.Sprintf("%s@%s", target.User, target.HostName)
+}
+
+var _ Connector = ExecConnector{}
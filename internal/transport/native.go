@@ -0,0 +1,266 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/term"
+
+	"wisshrd/internal/sshconf"
+)
+
+// NativeConnector connects with an in-process golang.org/x/crypto/ssh
+// client instead of shelling out to ssh(1). It supports agent auth,
+// IdentityFile keys, known_hosts verification, ProxyJump by dialing the
+// jump host and wrapping its net.Conn, and PTY allocation with resize
+// forwarding.
+type NativeConnector struct {
+	// KnownHostsFile defaults to ~/.ssh/known_hosts when empty.
+	KnownHostsFile string
+}
+
+// Connect dials target (through its ProxyJump, if any) and attaches an
+// interactive PTY session to the current process's stdio.
+func (n NativeConnector) Connect(target sshconf.EffectiveConfig) error {
+	if target.LocalForward != "" || target.RemoteForward != "" || target.DynamicForward != "" {
+		return fmt.Errorf("native connector does not yet support port forwarding, use the default exec connector instead")
+	}
+
+	clientConfig, err := n.clientConfig(target)
+	if err != nil {
+		return err
+	}
+
+	conn, err := n.dial(target, clientConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	session, err := conn.NewSession()
+	if err != nil {
+		return fmt.Errorf("could not open ssh session: %w", err)
+	}
+	defer session.Close()
+
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+	session.Stdin = os.Stdin
+
+	restore, err := n.attachPTY(session)
+	if err != nil {
+		return err
+	}
+	if restore != nil {
+		defer restore()
+	}
+
+	if err := session.Shell(); err != nil {
+		return fmt.Errorf("could not start remote shell: %w", err)
+	}
+
+	return session.Wait()
+}
+
+// dial connects to target, transparently tunneling through ProxyJump when
+// one is configured: it dials the jump host first, then dials the final
+// destination over that connection's net.Conn.
+func (n NativeConnector) dial(target sshconf.EffectiveConfig, clientConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	destAddr := net.JoinHostPort(target.HostName, orDefaultPort(target.Port))
+
+	if target.ProxyJump == "" {
+		client, err := ssh.Dial("tcp", destAddr, clientConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to %s: %w", destAddr, err)
+		}
+		return client, nil
+	}
+
+	jumpUser, jumpHost, jumpPort := parseProxyJump(target.ProxyJump)
+	jumpAddr := net.JoinHostPort(jumpHost, orDefaultPort(jumpPort))
+
+	jumpConfig, err := n.clientConfig(sshconf.EffectiveConfig{User: jumpUser})
+	if err != nil {
+		return nil, err
+	}
+
+	jumpClient, err := ssh.Dial("tcp", jumpAddr, jumpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to jump host %s: %w", jumpAddr, err)
+	}
+
+	conn, err := jumpClient.Dial("tcp", destAddr)
+	if err != nil {
+		jumpClient.Close()
+		return nil, fmt.Errorf("could not reach %s via jump host %s: %w", destAddr, jumpAddr, err)
+	}
+
+	ncc, chans, reqs, err := ssh.NewClientConn(conn, destAddr, clientConfig)
+	if err != nil {
+		jumpClient.Close()
+		return nil, fmt.Errorf("could not establish ssh session via jump host: %w", err)
+	}
+
+	return ssh.NewClient(ncc, chans, reqs), nil
+}
+
+// parseProxyJump splits an OpenSSH ProxyJump spec ("[user@]host[:port]")
+// into its parts. Missing parts are returned empty.
+func parseProxyJump(spec string) (user, host, port string) {
+	rest := spec
+	if idx := strings.Index(rest, "@"); idx != -1 {
+		user = rest[:idx]
+		rest = rest[idx+1:]
+	}
+
+	host = rest
+	if idx := strings.LastIndex(rest, ":"); idx != -1 {
+		host = rest[:idx]
+		port = rest[idx+1:]
+	}
+
+	return user, host, port
+}
+
+func orDefaultPort(port string) string {
+	if port == "" {
+		return "22"
+	}
+	return port
+}
+
+func (n NativeConnector) clientConfig(target sshconf.EffectiveConfig) (*ssh.ClientConfig, error) {
+	hostKeyCallback, err := n.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            target.User,
+		Auth:            n.authMethods(target),
+		HostKeyCallback: hostKeyCallback,
+	}, nil
+}
+
+// authMethods tries the ssh-agent first (SSH_AUTH_SOCK), then falls back
+// to the IdentityFile resolved from the user's ssh config.
+func (n NativeConnector) authMethods(target sshconf.EffectiveConfig) []ssh.AuthMethod {
+	var methods []ssh.AuthMethod
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			agentClient := agent.NewClient(conn)
+			methods = append(methods, ssh.PublicKeysCallback(agentClient.Signers))
+		}
+	}
+
+	if target.IdentityFile != "" {
+		if signer, err := loadIdentityFile(target.IdentityFile); err == nil {
+			methods = append(methods, ssh.PublicKeys(signer))
+		}
+	}
+
+	return methods
+}
+
+func loadIdentityFile(path string) (ssh.Signer, error) {
+	if len(path) >= 2 && path[:2] == "~/" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read identity file %q: %w", path, err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse identity file %q: %w", path, err)
+	}
+
+	return signer, nil
+}
+
+func (n NativeConnector) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	path := n.KnownHostsFile
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load known_hosts %q: %w", path, err)
+	}
+	return callback, nil
+}
+
+// attachPTY requests a remote PTY sized to the current terminal, puts the
+// local terminal into raw mode, and forwards SIGWINCH as PTY resizes. The
+// returned func restores the terminal and must be called when the
+// session ends.
+func (n NativeConnector) attachPTY(session *ssh.Session) (func(), error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, nil
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("could not set terminal to raw mode: %w", err)
+	}
+
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(os.Getenv("TERM"), height, width, modes); err != nil {
+		term.Restore(fd, oldState)
+		return nil, fmt.Errorf("could not request remote pty: %w", err)
+	}
+
+	sigwinch := make(chan os.Signal, 1)
+	signal.Notify(sigwinch, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigwinch:
+				if w, h, err := term.GetSize(fd); err == nil {
+					session.WindowChange(h, w)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		signal.Stop(sigwinch)
+		term.Restore(fd, oldState)
+	}, nil
+}
+
+var _ Connector = NativeConnector{}
@@ -0,0 +1,29 @@
+package transport
+
+import "testing"
+
+func TestParseProxyJump(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantUser string
+		wantHost string
+		wantPort string
+	}{
+		{"bare host", "bastion", "", "bastion", ""},
+		{"user and host", "jumpuser@bastion", "jumpuser", "bastion", ""},
+		{"host and port", "bastion:2222", "", "bastion", "2222"},
+		{"user, host and port", "jumpuser@bastion:2222", "jumpuser", "bastion", "2222"},
+		{"dotted host name and port", "jumpuser@bastion.example.com:22", "jumpuser", "bastion.example.com", "22"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, host, port := parseProxyJump(tt.spec)
+			if user != tt.wantUser || host != tt.wantHost || port != tt.wantPort {
+				t.Errorf("parseProxyJump(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.spec, user, host, port, tt.wantUser, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+}
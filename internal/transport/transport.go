@@ -0,0 +1,12 @@
+// Package transport connects to a remote host on behalf of wisshrd,
+// either by shelling out to the ssh(1) binary or by driving an in-process
+// golang.org/x/crypto/ssh client, behind a common Connector interface.
+package transport
+
+import "wisshrd/internal/sshconf"
+
+// Connector opens an interactive session to target and blocks until it
+// ends.
+type Connector interface {
+	Connect(target sshconf.EffectiveConfig) error
+}
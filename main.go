@@ -1,21 +1,38 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"wisshrd/internal/picker"
+	"wisshrd/internal/sshconf"
+	"wisshrd/internal/transport"
+)
+
+// frecencyLambda controls how fast a stored entry's score decays with
+// age; higher values favor recency over raw use count.
+const frecencyLambda = 0.05
+
+// defaultMaxPerBucket and defaultMaxAge bound how much history
+// saveStoredData keeps, so the file doesn't grow without bound.
+const (
+	defaultMaxPerBucket = 50
+	defaultMaxAge       = 90 * 24 * time.Hour
 )
 
 var (
 	version     = "0.0.0" // Will be set during build
 	showVersion = flag.Bool("version", false, "Show version information")
+	nativeMode  = flag.Bool("native", false, "Use the embedded fuzzy picker and native SSH client instead of fzf/ssh")
 )
 
 type SSHConfig struct {
@@ -23,12 +40,24 @@ type SSHConfig struct {
 	Accounts []StoredEntry
 	Hosts    []StoredEntry
 	Jumps    []StoredEntry
+	// HostConfigs maps a Hosts entry's display string (as produced by
+	// describeHost) back to the resolved ssh_config it was built from, so
+	// picking a display string can recover the real HostName/Port/etc.
+	// instead of connecting to the label itself. Entries sourced from
+	// history or typed freeform have no entry here.
+	HostConfigs map[string]sshconf.EffectiveConfig
+	// ParsedConfig is the full parsed ~/.ssh/config, kept so a host typed
+	// freeform (e.g. to instantiate a wildcard Host block) can still be
+	// resolved against it, rather than being treated as a literal hostname.
+	// Nil if the ssh_config file could not be loaded.
+	ParsedConfig *sshconf.Config
 }
 
 type StoredEntry struct {
 	Value     string    `json:"value"`
 	LastUsed  time.Time `json:"last_used"`
 	CreatedAt time.Time `json:"created_at"`
+	UseCount  int       `json:"use_count"`
 }
 
 type StoredData struct {
@@ -82,6 +111,8 @@ func loadStoredData() (*StoredData, error) {
 }
 
 func saveStoredData(data *StoredData) error {
+	PruneStoredData(data, defaultMaxPerBucket, defaultMaxAge)
+
 	configPath, err := getConfigPath()
 	if err != nil {
 		return err
@@ -99,9 +130,12 @@ func saveStoredData(data *StoredData) error {
 	return nil
 }
 
+// getValues returns entries' values ordered by descending frecency score,
+// so the most recent and most frequently used options surface first.
 func getValues(entries []StoredEntry) []string {
-	values := make([]string, len(entries))
-	for i, entry := range entries {
+	sorted := sortByFrecency(entries)
+	values := make([]string, len(sorted))
+	for i, entry := range sorted {
 		values[i] = entry.Value
 	}
 	return values
@@ -113,6 +147,7 @@ func createStoredEntry(value string) StoredEntry {
 		Value:     value,
 		LastUsed:  now,
 		CreatedAt: now,
+		UseCount:  1,
 	}
 }
 
@@ -121,6 +156,7 @@ func addOrUpdateEntry(entries []StoredEntry, value string) []StoredEntry {
 	for i, entry := range entries {
 		if entry.Value == value {
 			entries[i].LastUsed = now
+			entries[i].UseCount++
 			return entries
 		}
 	}
@@ -128,7 +164,99 @@ func addOrUpdateEntry(entries []StoredEntry, value string) []StoredEntry {
 		Value:     value,
 		LastUsed:  now,
 		CreatedAt: now,
+		UseCount:  1,
+	})
+}
+
+// frecencyScore combines use count and age into a single ranking score:
+// score = UseCount * exp(-lambda * age_days). More recent and more
+// frequently used entries score higher.
+func frecencyScore(entry StoredEntry, now time.Time) float64 {
+	ageDays := now.Sub(entry.LastUsed).Hours() / 24
+	if ageDays < 0 {
+		ageDays = 0
+	}
+	return float64(entry.UseCount) * math.Exp(-frecencyLambda*ageDays)
+}
+
+// sortByFrecency returns a copy of entries ordered by descending
+// frecencyScore.
+func sortByFrecency(entries []StoredEntry) []StoredEntry {
+	sorted := make([]StoredEntry, len(entries))
+	copy(sorted, entries)
+
+	now := time.Now()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return frecencyScore(sorted[i], now) > frecencyScore(sorted[j], now)
 	})
+
+	return sorted
+}
+
+// PruneStoredData evicts entries whose frecency score falls outside
+// maxPerBucket's cutoff or whose LastUsed is older than maxAge, keeping
+// each bucket in history.json bounded.
+func PruneStoredData(data *StoredData, maxPerBucket int, maxAge time.Duration) {
+	data.Keys = pruneBucket(data.Keys, maxPerBucket, maxAge)
+	data.Accounts = pruneBucket(data.Accounts, maxPerBucket, maxAge)
+	data.Hosts = pruneBucket(data.Hosts, maxPerBucket, maxAge)
+	data.Jumps = pruneBucket(data.Jumps, maxPerBucket, maxAge)
+}
+
+func pruneBucket(entries []StoredEntry, maxPerBucket int, maxAge time.Duration) []StoredEntry {
+	now := time.Now()
+
+	kept := make([]StoredEntry, 0, len(entries))
+	for _, entry := range entries {
+		if now.Sub(entry.LastUsed) > maxAge {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	sorted := sortByFrecency(kept)
+	if len(sorted) > maxPerBucket {
+		sorted = sorted[:maxPerBucket]
+	}
+
+	return sorted
+}
+
+// describeHost renders the fzf line for a resolved host: the effective
+// user@host:port, plus the jump host if one is configured.
+func describeHost(cfg sshconf.EffectiveConfig) string {
+	target := cfg.HostName
+	if cfg.User != "" {
+		target = fmt.Sprintf("%s@%s", cfg.User, target)
+	}
+	if port := sshconf.ParsePort(cfg); port != "" {
+		target = fmt.Sprintf("%s:%s", target, port)
+	}
+	if cfg.ProxyJump != "" {
+		target = fmt.Sprintf("%s -> %s", target, cfg.ProxyJump)
+	}
+	if cfg.Alias == "" || cfg.Alias == cfg.HostName {
+		return target
+	}
+	return fmt.Sprintf("%s (%s)", cfg.Alias, target)
+}
+
+// resolveHostSelection recovers the EffectiveConfig behind a picked Hosts
+// display string. If host isn't a known ssh_config entry, it is re-resolved
+// against the parsed config so a freeform value typed to instantiate a
+// wildcard Host block still picks up that block's User/Port/IdentityFile/
+// ProxyJump; failing that (history entries, or no ssh_config at all) it is
+// treated as a literal hostname.
+func resolveHostSelection(config *SSHConfig, host string) sshconf.EffectiveConfig {
+	if resolved, ok := config.HostConfigs[host]; ok {
+		return resolved
+	}
+	if config.ParsedConfig != nil {
+		if resolved, err := config.ParsedConfig.Resolve(host); err == nil {
+			return resolved
+		}
+	}
+	return sshconf.EffectiveConfig{Alias: host, HostName: host}
 }
 
 func loadSSHConfig() (*SSHConfig, error) {
@@ -138,10 +266,11 @@ func loadSSHConfig() (*SSHConfig, error) {
 	}
 
 	config := &SSHConfig{
-		Keys:     []StoredEntry{},
-		Accounts: []StoredEntry{},
-		Hosts:    []StoredEntry{},
-		Jumps:    []StoredEntry{},
+		Keys:        []StoredEntry{},
+		Accounts:    []StoredEntry{},
+		Hosts:       []StoredEntry{},
+		Jumps:       []StoredEntry{},
+		HostConfigs: map[string]sshconf.EffectiveConfig{},
 	}
 
 	// Add current user as the primary option for keys
@@ -150,26 +279,32 @@ func loadSSHConfig() (*SSHConfig, error) {
 		config.Keys = append(config.Keys, createStoredEntry(currentUser.Username))
 	}
 
-	// Read SSH config file for service users and hosts
-	sshConfigPath := filepath.Join(homeDir, ".ssh", "config")
-	if file, err := os.Open(sshConfigPath); err == nil {
-		defer file.Close()
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if strings.HasPrefix(line, "Host ") {
-				host := strings.TrimPrefix(line, "Host ")
-				if !strings.Contains(host, "*") {
-					for _, h := range strings.Fields(host) {
-						config.Hosts = append(config.Hosts, createStoredEntry(h))
-					}
-				}
-			} else if strings.HasPrefix(line, "User ") {
-				user := strings.TrimPrefix(line, "User ")
-				config.Accounts = append(config.Accounts, createStoredEntry(user))
-			} else if strings.HasPrefix(line, "ProxyJump ") {
-				jump := strings.TrimPrefix(line, "ProxyJump ")
-				config.Jumps = append(config.Jumps, createStoredEntry(jump))
+	// Read SSH config file for service users, hosts and jump hosts
+	sshDir := filepath.Join(homeDir, ".ssh")
+	sshConfigPath := filepath.Join(sshDir, "config")
+	if cfg, err := sshconf.LoadFile(sshConfigPath, sshDir); err == nil {
+		config.ParsedConfig = cfg
+
+		// Wildcard Host blocks aren't listed: there is no single concrete
+		// value to show or connect to. Instead, a host typed freeform into
+		// fzf's --print-query field (see runFzf) is re-resolved against
+		// ParsedConfig by resolveHostSelection, which applies any matching
+		// wildcard block's settings.
+		concrete, _ := cfg.Aliases()
+
+		for _, alias := range concrete {
+			resolved, err := cfg.Resolve(alias)
+			if err != nil {
+				continue
+			}
+			label := describeHost(resolved)
+			config.Hosts = append(config.Hosts, createStoredEntry(label))
+			config.HostConfigs[label] = resolved
+			if resolved.User != "" {
+				config.Accounts = append(config.Accounts, createStoredEntry(resolved.User))
+			}
+			if resolved.ProxyJump != "" {
+				config.Jumps = append(config.Jumps, createStoredEntry(resolved.ProxyJump))
 			}
 		}
 	}
@@ -186,51 +321,26 @@ func loadSSHConfig() (*SSHConfig, error) {
 	return config, nil
 }
 
-func runFzf(items []string, prompt string) (string, error) {
-	args := []string{
-		"--height", "20%",
-		"--min-height", "1",
-		"--print-query",
-		"--no-margin",
-		"--no-padding",
-		"--prompt", fmt.Sprintf("%s (%d options) > ", prompt, len(items)),
-	}
-	cmd := exec.Command("fzf", args...)
-
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return "", err
-	}
-
-	go func() {
-		defer stdin.Close()
-		for _, item := range items {
-			fmt.Fprintln(stdin, item)
-		}
-	}()
-
-	output, err := cmd.Output()
-	if err != nil {
-		exitErr, ok := err.(*exec.ExitError)
-		if ok && exitErr.ExitCode() == 1 {
-			lines := strings.Split(string(output), "\n")
-			if len(lines) > 0 && lines[0] != "" {
-				return lines[0], nil
-			}
-		}
-		return "", err
+// selectPicker returns the fzf-backed picker by default, or the embedded
+// zero-dependency one when --native is set.
+func selectPicker() picker.Picker {
+	if *nativeMode {
+		return picker.EmbeddedPicker{}
 	}
+	return picker.FzfPicker{}
+}
 
-	lines := strings.Split(string(output), "\n")
-	if len(lines) >= 2 {
-		selection := strings.TrimSpace(lines[1])
-		if selection != "" {
-			return selection, nil
-		}
-		return strings.TrimSpace(lines[0]), nil
+// selectConnector returns the exec-based ssh(1) connector by default, or
+// the in-process golang.org/x/crypto/ssh client when --native is set.
+func selectConnector() transport.Connector {
+	if *nativeMode {
+		return transport.NativeConnector{}
 	}
+	return transport.ExecConnector{}
+}
 
-	return strings.TrimSpace(string(output)), nil
+func runFzf(items []string, prompt string) (string, error) {
+	return selectPicker().Pick(items, prompt)
 }
 
 func promptConfirmation(sshCmd string) bool {
@@ -240,8 +350,8 @@ func promptConfirmation(sshCmd string) bool {
 	return strings.ToLower(response) == "y"
 }
 
-func executeSSH(sshCmd string) error {
-	cmd := exec.Command("ssh", sshCmd)
+func executeSSH(args []string) error {
+	cmd := exec.Command("ssh", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stdin = os.Stdin
 	cmd.Stderr = os.Stderr
@@ -251,6 +361,46 @@ func executeSSH(sshCmd string) error {
 func main() {
 	flag.Parse()
 
+	if flag.Arg(0) == "server" {
+		if err := runServerCommand(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "history" {
+		if err := runHistoryCommand(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "save" {
+		if err := runSaveCommand(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "connect" {
+		if err := runConnectCommand(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "profiles" {
+		if err := runProfilesCommand(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *showVersion {
 		fmt.Printf("wisshrd version %s\n", version)
 		os.Exit(0)
@@ -289,6 +439,7 @@ func main() {
 		os.Exit(1)
 	}
 	storedData.Hosts = addOrUpdateEntry(storedData.Hosts, host)
+	resolvedHost := resolveHostSelection(config, host)
 
 	// Select jump host
 	jump, err := runFzf(getValues(config.Jumps), "jump")
@@ -303,18 +454,66 @@ func main() {
 	// Save updated data
 	saveStoredData(storedData)
 
-	// Build the SSH command
-	sshCmd := fmt.Sprintf("%s@%s@%s", key, account, host)
+	if *nativeMode {
+		target := sshconf.EffectiveConfig{
+			User:           account,
+			HostName:       resolvedHost.HostName,
+			Port:           resolvedHost.Port,
+			IdentityFile:   resolvedHost.IdentityFile,
+			ProxyJump:      jump,
+			LocalForward:   resolvedHost.LocalForward,
+			RemoteForward:  resolvedHost.RemoteForward,
+			DynamicForward: resolvedHost.DynamicForward,
+		}
+		if !promptConfirmation(describeHost(target)) {
+			fmt.Println("Connection cancelled")
+			return
+		}
+		if err := selectConnector().Connect(target); err != nil {
+			fmt.Fprintf(os.Stderr, "Error connecting: %v\n", err)
+			os.Exit(1)
+		}
+		recordLastConnection(Profile{
+			Key:          key,
+			Account:      account,
+			Host:         resolvedHost.HostName,
+			Jump:         jump,
+			Port:         resolvedHost.Port,
+			IdentityFile: resolvedHost.IdentityFile,
+		})
+		return
+	}
+
+	// Build the SSH command. The resolved Port/IdentityFile are passed as
+	// real ssh(1) flags instead of relying on ssh to re-match the
+	// destination against ~/.ssh/config itself, since the destination here
+	// is the already-resolved HostName rather than the original alias.
+	dest := fmt.Sprintf("%s@%s@%s", key, account, resolvedHost.HostName)
 	if jump != "" {
-		sshCmd = fmt.Sprintf("%s@%s", sshCmd, jump)
+		dest = fmt.Sprintf("%s@%s", dest, jump)
+	}
+	args := []string{dest}
+	if resolvedHost.Port != "" {
+		args = append(args, "-p", resolvedHost.Port)
+	}
+	if resolvedHost.IdentityFile != "" {
+		args = append(args, "-i", resolvedHost.IdentityFile)
 	}
 
 	// Show the command and prompt for confirmation
-	if promptConfirmation(sshCmd) {
-		if err := executeSSH(sshCmd); err != nil {
+	if promptConfirmation(strings.Join(args, " ")) {
+		if err := executeSSH(args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error executing SSH command: %v\n", err)
 			os.Exit(1)
 		}
+		recordLastConnection(Profile{
+			Key:          key,
+			Account:      account,
+			Host:         resolvedHost.HostName,
+			Jump:         jump,
+			Port:         resolvedHost.Port,
+			IdentityFile: resolvedHost.IdentityFile,
+		})
 	} else {
 		fmt.Println("Connection cancelled")
 	}
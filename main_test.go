@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrecencyScore_DecaysWithAge(t *testing.T) {
+	now := time.Now()
+
+	fresh := StoredEntry{UseCount: 1, LastUsed: now}
+	stale := StoredEntry{UseCount: 1, LastUsed: now.Add(-30 * 24 * time.Hour)}
+
+	freshScore := frecencyScore(fresh, now)
+	staleScore := frecencyScore(stale, now)
+
+	if freshScore <= staleScore {
+		t.Errorf("fresh score %v should be greater than stale score %v", freshScore, staleScore)
+	}
+}
+
+func TestFrecencyScore_HigherUseCountScoresHigher(t *testing.T) {
+	now := time.Now()
+
+	used := StoredEntry{UseCount: 10, LastUsed: now.Add(-10 * 24 * time.Hour)}
+	rare := StoredEntry{UseCount: 1, LastUsed: now.Add(-10 * 24 * time.Hour)}
+
+	if frecencyScore(used, now) <= frecencyScore(rare, now) {
+		t.Error("entry used more often should score higher at the same age")
+	}
+}
+
+func TestFrecencyScore_FutureLastUsedClampedToZeroAge(t *testing.T) {
+	now := time.Now()
+	entry := StoredEntry{UseCount: 1, LastUsed: now.Add(1 * time.Hour)}
+
+	// A LastUsed slightly in the future (e.g. clock skew) should be
+	// treated as age zero rather than producing a score above what a
+	// same-UseCount entry used right now would get.
+	zeroAge := StoredEntry{UseCount: 1, LastUsed: now}
+	if frecencyScore(entry, now) != frecencyScore(zeroAge, now) {
+		t.Error("future LastUsed should clamp to age zero, not boost the score further")
+	}
+}
+
+func TestSortByFrecency_OrdersByDescendingScore(t *testing.T) {
+	now := time.Now()
+	entries := []StoredEntry{
+		{Value: "old", UseCount: 1, LastUsed: now.Add(-60 * 24 * time.Hour)},
+		{Value: "frequent", UseCount: 20, LastUsed: now.Add(-5 * 24 * time.Hour)},
+		{Value: "recent", UseCount: 1, LastUsed: now},
+	}
+
+	sorted := sortByFrecency(entries)
+
+	if len(sorted) != len(entries) {
+		t.Fatalf("sortByFrecency changed length: got %d, want %d", len(sorted), len(entries))
+	}
+	if sorted[len(sorted)-1].Value != "old" {
+		t.Errorf("least recently/frequently used entry should sort last, got order %v", valuesOf(sorted))
+	}
+}
+
+func TestSortByFrecency_DoesNotMutateInput(t *testing.T) {
+	entries := []StoredEntry{
+		{Value: "a", UseCount: 1, LastUsed: time.Now()},
+		{Value: "b", UseCount: 5, LastUsed: time.Now()},
+	}
+	original := append([]StoredEntry(nil), entries...)
+
+	sortByFrecency(entries)
+
+	for i := range entries {
+		if entries[i].Value != original[i].Value {
+			t.Error("sortByFrecency must not reorder the caller's slice in place")
+		}
+	}
+}
+
+func TestPruneBucket_EvictsEntriesOlderThanMaxAge(t *testing.T) {
+	now := time.Now()
+	entries := []StoredEntry{
+		{Value: "fresh", UseCount: 1, LastUsed: now},
+		{Value: "expired", UseCount: 1, LastUsed: now.Add(-100 * 24 * time.Hour)},
+	}
+
+	kept := pruneBucket(entries, 50, 90*24*time.Hour)
+
+	if len(kept) != 1 || kept[0].Value != "fresh" {
+		t.Errorf("pruneBucket kept %v, want only the non-expired entry", valuesOf(kept))
+	}
+}
+
+func TestPruneBucket_CapsAtMaxPerBucket(t *testing.T) {
+	now := time.Now()
+	var entries []StoredEntry
+	for i := 0; i < 5; i++ {
+		entries = append(entries, StoredEntry{
+			Value:    string(rune('a' + i)),
+			UseCount: i + 1,
+			LastUsed: now,
+		})
+	}
+
+	kept := pruneBucket(entries, 2, 90*24*time.Hour)
+
+	if len(kept) != 2 {
+		t.Fatalf("pruneBucket returned %d entries, want 2", len(kept))
+	}
+	// Highest UseCount entries should survive the cutoff.
+	if kept[0].Value != "e" || kept[1].Value != "d" {
+		t.Errorf("pruneBucket kept %v, want the highest-scoring entries first", valuesOf(kept))
+	}
+}
+
+func valuesOf(entries []StoredEntry) []string {
+	values := make([]string, len(entries))
+	for i, e := range entries {
+		values[i] = e.Value
+	}
+	return values
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"wisshrd/internal/sshconf"
+)
+
+// Profile bundles everything needed to reconnect to a host in one step:
+// the key/account/host/jump picked through the normal fzf flow, plus the
+// optional extras a raw ssh_config Host block can carry.
+type Profile struct {
+	Name           string    `json:"name"`
+	Key            string    `json:"key,omitempty"`
+	Account        string    `json:"account,omitempty"`
+	Host           string    `json:"host,omitempty"`
+	Jump           string    `json:"jump,omitempty"`
+	Port           string    `json:"port,omitempty"`
+	IdentityFile   string    `json:"identity_file,omitempty"`
+	LocalForward   string    `json:"local_forward,omitempty"`
+	RemoteForward  string    `json:"remote_forward,omitempty"`
+	DynamicForward string    `json:"dynamic_forward,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastUsed       time.Time `json:"last_used"`
+}
+
+// ProfileStore is the on-disk representation of ~/.config/wisshrd/profiles.json.
+type ProfileStore struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// target renders the profile as the EffectiveConfig a Connector expects.
+func (p Profile) target() sshconf.EffectiveConfig {
+	return sshconf.EffectiveConfig{
+		Alias:          p.Name,
+		HostName:       p.Host,
+		User:           p.Account,
+		Port:           p.Port,
+		IdentityFile:   p.IdentityFile,
+		ProxyJump:      p.Jump,
+		LocalForward:   p.LocalForward,
+		RemoteForward:  p.RemoteForward,
+		DynamicForward: p.DynamicForward,
+	}
+}
+
+func getProfilesPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".config", "wisshrd")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return "", fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "profiles.json"), nil
+}
+
+func loadProfiles() (*ProfileStore, error) {
+	store := &ProfileStore{Profiles: []Profile{}}
+
+	path, err := getProfilesPath()
+	if err != nil {
+		return store, err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return store, nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return store, fmt.Errorf("could not read profiles file: %w", err)
+	}
+
+	if err := json.Unmarshal(file, store); err != nil {
+		return store, fmt.Errorf("could not parse profiles file: %w", err)
+	}
+
+	return store, nil
+}
+
+func saveProfiles(store *ProfileStore) error {
+	path, err := getProfilesPath()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal profiles: %w", err)
+	}
+
+	if err := os.WriteFile(path, jsonData, 0600); err != nil {
+		return fmt.Errorf("could not write profiles file: %w", err)
+	}
+
+	return nil
+}
+
+func findProfile(store *ProfileStore, name string) (int, bool) {
+	for i, p := range store.Profiles {
+		if p.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func getLastConnectionPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".config", "wisshrd")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return "", fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "last_connection.json"), nil
+}
+
+// recordLastConnection persists the most recent successful connection, so
+// `wisshrd save <name>` can turn it into a named profile.
+func recordLastConnection(p Profile) error {
+	path, err := getLastConnectionPath()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal last connection: %w", err)
+	}
+
+	return os.WriteFile(path, jsonData, 0600)
+}
+
+func loadLastConnection() (Profile, error) {
+	path, err := getLastConnectionPath()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("no successful connection recorded yet: %w", err)
+	}
+
+	var p Profile
+	if err := json.Unmarshal(file, &p); err != nil {
+		return Profile{}, fmt.Errorf("could not parse last connection: %w", err)
+	}
+
+	return p, nil
+}
+
+// runSaveCommand implements `wisshrd save <name>`: it captures the last
+// successful connection and stores it as a named profile.
+func runSaveCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: wisshrd save <name>")
+	}
+	name := args[0]
+
+	last, err := loadLastConnection()
+	if err != nil {
+		return err
+	}
+
+	store, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	last.Name = name
+	last.CreatedAt = time.Now()
+	last.LastUsed = last.CreatedAt
+
+	if i, ok := findProfile(store, name); ok {
+		store.Profiles[i] = last
+	} else {
+		store.Profiles = append(store.Profiles, last)
+	}
+
+	if err := saveProfiles(store); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved profile %q\n", name)
+	return nil
+}
+
+// runConnectCommand implements `wisshrd connect <name>`: it replays a
+// saved profile's connection.
+func runConnectCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: wisshrd connect <name>")
+	}
+	name := args[0]
+
+	store, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	i, ok := findProfile(store, name)
+	if !ok {
+		return fmt.Errorf("no profile named %q (run `wisshrd profiles` to list saved profiles)", name)
+	}
+
+	profile := store.Profiles[i]
+	target := profile.target()
+
+	if !promptConfirmation(describeHost(target)) {
+		fmt.Println("Connection cancelled")
+		return nil
+	}
+
+	if err := selectConnector().Connect(target); err != nil {
+		return fmt.Errorf("error connecting: %w", err)
+	}
+
+	store.Profiles[i].LastUsed = time.Now()
+	return saveProfiles(store)
+}
+
+// runProfilesCommand implements `wisshrd profiles` (fzf-pick and connect)
+// and `wisshrd profiles export` (emit ~/.ssh/config Host blocks).
+func runProfilesCommand(args []string) error {
+	if len(args) > 0 && args[0] == "export" {
+		return exportProfiles()
+	}
+
+	store, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	if len(store.Profiles) == 0 {
+		fmt.Println("No saved profiles. Use `wisshrd save <name>` after connecting.")
+		return nil
+	}
+
+	names := make([]string, len(store.Profiles))
+	for i, p := range store.Profiles {
+		names[i] = p.Name
+	}
+
+	choice, err := selectPicker().Pick(names, "profile")
+	if err != nil {
+		return fmt.Errorf("could not select profile: %w", err)
+	}
+
+	return runConnectCommand([]string{choice})
+}
+
+// exportBeginMarker and exportEndMarker delimit the block exportProfiles
+// generates inside ~/.ssh/config, so a later export can find and replace
+// its own output instead of appending a duplicate copy forever.
+const (
+	exportBeginMarker = "# >>> wisshrd profiles (generated by `wisshrd profiles export`) >>>"
+	exportEndMarker   = "# <<< wisshrd profiles end <<<"
+)
+
+// exportProfiles emits each saved profile as an equivalent Host block in
+// ~/.ssh/config, so other ssh_config-aware tools pick up the same
+// shortcuts. Re-running it replaces the previously generated block
+// in place rather than appending a duplicate.
+func exportProfiles() error {
+	store, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	if len(store.Profiles) == 0 {
+		fmt.Println("No saved profiles to export.")
+		return nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("could not get home directory: %w", err)
+	}
+	sshConfigPath := filepath.Join(homeDir, ".ssh", "config")
+
+	existing, err := os.ReadFile(sshConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", sshConfigPath, err)
+	}
+
+	updated := replaceExportBlock(string(existing), buildExportBlock(store.Profiles))
+
+	// Write to a temp file in the same directory and rename into place, so
+	// a crash or full disk mid-write can't truncate the user's real
+	// ~/.ssh/config.
+	tmpPath := sshConfigPath + ".wisshrd-tmp"
+	if err := os.WriteFile(tmpPath, []byte(updated), 0600); err != nil {
+		return fmt.Errorf("could not write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, sshConfigPath); err != nil {
+		return fmt.Errorf("could not replace %s: %w", sshConfigPath, err)
+	}
+
+	fmt.Printf("Exported %d profile(s) to %s\n", len(store.Profiles), sshConfigPath)
+	return nil
+}
+
+func buildExportBlock(profiles []Profile) string {
+	var block strings.Builder
+	block.WriteString(exportBeginMarker + "\n")
+	for _, p := range profiles {
+		block.WriteString(fmt.Sprintf("Host %s\n", p.Name))
+		writeIfSet(&block, "HostName", p.Host)
+		writeIfSet(&block, "User", p.Account)
+		writeIfSet(&block, "Port", p.Port)
+		writeIfSet(&block, "IdentityFile", p.IdentityFile)
+		writeIfSet(&block, "ProxyJump", p.Jump)
+		writeIfSet(&block, "LocalForward", p.LocalForward)
+		writeIfSet(&block, "RemoteForward", p.RemoteForward)
+		writeIfSet(&block, "DynamicForward", p.DynamicForward)
+	}
+	block.WriteString(exportEndMarker + "\n")
+	return block.String()
+}
+
+// replaceExportBlock swaps any previously generated block (delimited by
+// exportBeginMarker/exportEndMarker) in content for block at the same
+// position, or appends block if there is none, so repeated exports don't
+// duplicate entries.
+func replaceExportBlock(content, block string) string {
+	start := strings.Index(content, exportBeginMarker)
+	if start != -1 {
+		if end := strings.Index(content[start:], exportEndMarker); end != -1 {
+			after := start + end + len(exportEndMarker)
+			if after < len(content) && content[after] == '\n' {
+				after++
+			}
+			return content[:start] + block + content[after:]
+		}
+	}
+
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		content += "\n\n"
+	}
+	return content + block
+}
+
+func writeIfSet(b *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "  %s %s\n", key, value)
+}
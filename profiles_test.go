@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestFindProfile(t *testing.T) {
+	store := &ProfileStore{Profiles: []Profile{{Name: "prod"}, {Name: "staging"}}}
+
+	i, ok := findProfile(store, "staging")
+	if !ok || i != 1 {
+		t.Errorf("findProfile(staging) = (%d, %v), want (1, true)", i, ok)
+	}
+
+	if _, ok := findProfile(store, "missing"); ok {
+		t.Error("findProfile(missing) should return ok=false")
+	}
+}
+
+func TestBuildExportBlock(t *testing.T) {
+	profiles := []Profile{
+		{Name: "prod", Host: "10.0.0.1", Account: "deploy", Port: "2222"},
+	}
+
+	got := buildExportBlock(profiles)
+	want := exportBeginMarker + "\n" +
+		"Host prod\n" +
+		"  HostName 10.0.0.1\n" +
+		"  User deploy\n" +
+		"  Port 2222\n" +
+		exportEndMarker + "\n"
+
+	if got != want {
+		t.Errorf("buildExportBlock() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildExportBlock_OmitsUnsetFields(t *testing.T) {
+	profiles := []Profile{{Name: "bare", Host: "10.0.0.1"}}
+
+	got := buildExportBlock(profiles)
+	if got != exportBeginMarker+"\nHost bare\n  HostName 10.0.0.1\n"+exportEndMarker+"\n" {
+		t.Errorf("buildExportBlock() with only Host set = %q", got)
+	}
+}
+
+func TestReplaceExportBlock_AppendsWhenNoPriorBlock(t *testing.T) {
+	existing := "Host manual\n  HostName 1.2.3.4\n"
+	block := exportBeginMarker + "\nHost prod\n" + exportEndMarker + "\n"
+
+	got := replaceExportBlock(existing, block)
+	want := existing + "\n" + block
+
+	if got != want {
+		t.Errorf("replaceExportBlock() = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceExportBlock_ReplacesPriorBlockInPlace(t *testing.T) {
+	oldBlock := exportBeginMarker + "\nHost old\n" + exportEndMarker + "\n"
+	existing := "Host manual\n  HostName 1.2.3.4\n\n" + oldBlock
+	newBlock := exportBeginMarker + "\nHost new\n" + exportEndMarker + "\n"
+
+	got := replaceExportBlock(existing, newBlock)
+
+	want := "Host manual\n  HostName 1.2.3.4\n\n" + newBlock
+	if got != want {
+		t.Errorf("replaceExportBlock() = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceExportBlock_RunTwiceIsIdempotent(t *testing.T) {
+	block := exportBeginMarker + "\nHost prod\n" + exportEndMarker + "\n"
+
+	once := replaceExportBlock("", block)
+	twice := replaceExportBlock(once, block)
+
+	if once != twice {
+		t.Errorf("replaceExportBlock should be idempotent: first run %q, second run %q", once, twice)
+	}
+}
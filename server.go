@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"wisshrd/internal/remoteadmin"
+)
+
+// runServerCommand implements `wisshrd server`: it reuses the normal
+// fzf host-selection flow to pick a target, then opens the sshd_config
+// management TUI against it.
+func runServerCommand() error {
+	config, err := loadSSHConfig()
+	if err != nil {
+		return fmt.Errorf("could not load SSH config: %w", err)
+	}
+
+	host, err := runFzf(getValues(config.Hosts), "host")
+	if err != nil {
+		return fmt.Errorf("could not select host: %w", err)
+	}
+	resolvedHost := resolveHostSelection(config, host)
+
+	account, err := runFzf(getValues(config.Accounts), "account")
+	if err != nil {
+		return fmt.Errorf("could not select account: %w", err)
+	}
+
+	target := resolvedHost.HostName
+	if account != "" {
+		target = fmt.Sprintf("%s@%s", account, resolvedHost.HostName)
+	}
+
+	return runServerMode(target)
+}
+
+// runServerMode opens the sshd_config management TUI for target, the
+// user@host string produced by the normal fzf host-selection flow.
+func runServerMode(target string) error {
+	admin := remoteadmin.NewExecAdmin(target)
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		info, err := admin.GetInfo()
+		if err != nil {
+			return fmt.Errorf("could not read sshd_config on %s: %w", target, err)
+		}
+
+		fmt.Printf("\n%s sshd_config\n", target)
+		fmt.Printf("  1) Port                   %s\n", info.Port)
+		fmt.Printf("  2) ListenAddress          %s\n", info.ListenAddress)
+		fmt.Printf("  3) PasswordAuthentication %s\n", info.PasswordAuthentication)
+		fmt.Printf("  4) PubkeyAuthentication   %s\n", info.PubkeyAuthentication)
+		fmt.Printf("  5) PermitRootLogin        %s\n", info.PermitRootLogin)
+		fmt.Printf("  6) UseDNS                 %s\n", info.UseDNS)
+		fmt.Printf("  g) Generate keypair and install it\n")
+		fmt.Printf("  l) Tail auth log\n")
+		fmt.Printf("  q) Quit\n")
+		fmt.Print("> ")
+
+		choice, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		choice = strings.TrimSpace(choice)
+
+		var key string
+		switch choice {
+		case "1":
+			key = "Port"
+		case "2":
+			key = "ListenAddress"
+		case "3":
+			key = "PasswordAuthentication"
+		case "4":
+			key = "PubkeyAuthentication"
+		case "5":
+			key = "PermitRootLogin"
+		case "6":
+			key = "UseDNS"
+		case "g":
+			if err := generateAndInstallKey(admin); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating key: %v\n", err)
+			}
+			continue
+		case "l":
+			if err := tailAuthLog(admin); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading auth log: %v\n", err)
+			}
+			continue
+		case "q", "":
+			return nil
+		default:
+			fmt.Println("Unrecognized option")
+			continue
+		}
+
+		fmt.Printf("New value for %s: ", key)
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSpace(value)
+
+		if !promptConfirmation(fmt.Sprintf("set %s %s on %s", key, value, target)) {
+			fmt.Println("Change cancelled")
+			continue
+		}
+
+		if err := admin.Update(key, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating sshd_config: %v\n", err)
+		}
+	}
+}
+
+func generateAndInstallKey(admin remoteadmin.ISSHAdmin) error {
+	if !promptConfirmation("generate a new keypair and append it to authorized_keys") {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	pubKey, err := admin.GenerateKey("wisshrd")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed public key:\n%s\n", pubKey)
+	return nil
+}
+
+func tailAuthLog(admin remoteadmin.ISSHAdmin) error {
+	entries, err := admin.LoadLog("sshd", remoteadmin.NewHTTPGeoLocator())
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IP == "" {
+			fmt.Println(entry.Raw)
+			continue
+		}
+		fmt.Printf("%s [%s]\n", entry.Raw, entry.Location)
+	}
+
+	return nil
+}